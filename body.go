@@ -0,0 +1,26 @@
+package raven
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// CaptureBody reads up to limit bytes of r's body, restores r.Body so
+// downstream handlers can still read it in full, and returns what was
+// captured. A limit <= 0 disables capture and returns "" without touching
+// r.Body.
+func CaptureBody(r *http.Request, limit int64) string {
+	if limit <= 0 || r.Body == nil {
+		return ""
+	}
+
+	captured, err := ioutil.ReadAll(io.LimitReader(r.Body, limit))
+	if err != nil {
+		return ""
+	}
+
+	r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(captured), r.Body))
+	return string(captured)
+}
@@ -0,0 +1,115 @@
+package raven
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultMaxBreadcrumbs is how many breadcrumbs a Scope keeps once no
+// Client override has been set via SetMaxBreadcrumbs.
+const defaultMaxBreadcrumbs = 100
+
+// Breadcrumb is a single point of context recorded before an event,
+// matching Sentry's breadcrumbs interface.
+// https://docs.getsentry.com/hosted/clientdev/interfaces/#breadcrumbs-interface
+type Breadcrumb struct {
+	Timestamp time.Time
+	Category  string
+	Message   string
+	Level     string
+	Type      string
+	Data      map[string]interface{}
+}
+
+// MarshalJSON renders Timestamp as the Unix time Sentry's protocol
+// expects rather than Go's default RFC 3339 string.
+func (b Breadcrumb) MarshalJSON() ([]byte, error) {
+	type alias struct {
+		Timestamp int64                  `json:"timestamp"`
+		Category  string                 `json:"category,omitempty"`
+		Message   string                 `json:"message,omitempty"`
+		Level     string                 `json:"level,omitempty"`
+		Type      string                 `json:"type,omitempty"`
+		Data      map[string]interface{} `json:"data,omitempty"`
+	}
+	return json.Marshal(alias{
+		Timestamp: b.Timestamp.Unix(),
+		Category:  b.Category,
+		Message:   b.Message,
+		Level:     b.Level,
+		Type:      b.Type,
+		Data:      b.Data,
+	})
+}
+
+// setMaxBreadcrumbs sets how many breadcrumbs are kept per Scope; once
+// the limit is hit, the oldest breadcrumb is dropped for each new one
+// added. n <= 0 restores the default of 100. Unexported because only
+// DefaultClient's limit is ever consulted (by AddBreadcrumb); there is no
+// way to make a per-Client setting here actually take effect without
+// threading the owning Client through AddBreadcrumb, so it isn't exposed
+// as part of the Client API.
+func (client *Client) setMaxBreadcrumbs(n int) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.maxBreadcrumbs = n
+}
+
+func (client *Client) getMaxBreadcrumbs() int {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.maxBreadcrumbs <= 0 {
+		return defaultMaxBreadcrumbs
+	}
+	return client.maxBreadcrumbs
+}
+
+// SetMaxBreadcrumbs sets the per-Scope breadcrumb limit used by the
+// package-level API.
+func SetMaxBreadcrumbs(n int) {
+	DefaultClient.setMaxBreadcrumbs(n)
+}
+
+// breadcrumbTransport is the http.RoundTripper returned by WrapTransport.
+type breadcrumbTransport struct {
+	base http.RoundTripper
+}
+
+// WrapTransport returns an http.RoundTripper wrapping base (or
+// http.DefaultTransport if base is nil) that records each outgoing
+// request as an "http" category breadcrumb - method, URL, status code,
+// and duration - on the Scope carried by the request's context, if any.
+func WrapTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &breadcrumbTransport{base: base}
+}
+
+func (t *breadcrumbTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	data := map[string]interface{}{
+		"method":      req.Method,
+		"url":         req.URL.String(),
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		data["error"] = err.Error()
+	} else {
+		data["status_code"] = resp.StatusCode
+	}
+
+	AddBreadcrumb(req.Context(), Breadcrumb{
+		Category: "http",
+		Type:     "http",
+		Level:    "info",
+		Message:  fmt.Sprintf("%s %s", req.Method, req.URL.String()),
+		Data:     data,
+	})
+
+	return resp, err
+}
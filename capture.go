@@ -0,0 +1,114 @@
+package raven
+
+import (
+	"context"
+	"net/http"
+)
+
+// UserExtractor pulls a *User out of an inbound request, e.g. from a JWT
+// claim or session cookie. CaptureRequest calls it (see WithUserExtractor)
+// once for each event it captures and attaches the result to that event.
+type UserExtractor func(*http.Request) *User
+
+// CaptureOption configures a single call to CaptureRequest.
+type CaptureOption func(*captureOptions)
+
+type captureOptions struct {
+	request       *http.Request
+	body          string
+	userExtractor UserExtractor
+	tags          map[string]string
+	culprit       string
+}
+
+// WithRequest attaches the inbound *http.Request, populating Http.* fields
+// on the outgoing Packet via NewHttp.
+func WithRequest(r *http.Request) CaptureOption {
+	return func(o *captureOptions) { o.request = r }
+}
+
+// WithBody attaches a captured request body to Http.Data. It has no effect
+// unless WithRequest is also given. Use CaptureBody to produce body while
+// restoring the request's Reader for downstream handlers.
+func WithBody(body string) CaptureOption {
+	return func(o *captureOptions) { o.body = body }
+}
+
+// WithUserExtractor derives the reporting User from the request. It has no
+// effect unless WithRequest is also given.
+func WithUserExtractor(extract UserExtractor) CaptureOption {
+	return func(o *captureOptions) { o.userExtractor = extract }
+}
+
+// WithTags merges extra tags onto the captured event, e.g. "endpoint" and
+// the matched route pattern.
+func WithTags(tags map[string]string) CaptureOption {
+	return func(o *captureOptions) {
+		if o.tags == nil {
+			o.tags = make(map[string]string, len(tags))
+		}
+		for k, v := range tags {
+			o.tags[k] = v
+		}
+	}
+}
+
+// WithCulprit sets the packet's culprit, which is otherwise left empty.
+func WithCulprit(culprit string) CaptureOption {
+	return func(o *captureOptions) { o.culprit = culprit }
+}
+
+// CaptureRequest reports err to Sentry with HTTP and user context, without
+// requiring a panic. The middleware/ subpackages use it to forward
+// non-fatal errors surfaced through a framework's own error chain (e.g.
+// Gin's c.Errors) alongside the recovered-panic path handled by
+// DealWithRecoveredValue. If ctx carries a Scope (see NewContext), its
+// tags, extra data, HTTP context, and user fill in for anything not given
+// explicitly via opts. Reports through the package-level Capture (i.e.
+// DefaultClient); a captured body is scrubbed with DefaultClient's
+// Scrubber regardless of which Client, if any, a caller has configured
+// separately.
+func CaptureRequest(ctx context.Context, err error, opts ...CaptureOption) (eventID string, ch chan error) {
+	o := &captureOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	scope := FromContext(ctx)
+	scopeHttp, scopeUser := scope.httpAndUser()
+	interfaces := []Interface{NewException(err, GetOrNewStacktrace(err, 2, 3, nil))}
+
+	switch {
+	case o.request != nil:
+		httpInterface := NewHttp(o.request)
+		if o.body != "" {
+			httpInterface.Data = DefaultClient.getScrubber().ScrubData(o.body)
+		}
+		interfaces = append(interfaces, httpInterface)
+	case scopeHttp != nil:
+		interfaces = append(interfaces, scopeHttp)
+	}
+
+	switch {
+	case o.request != nil && o.userExtractor != nil:
+		if user := o.userExtractor(o.request); user != nil {
+			interfaces = append(interfaces, user)
+		}
+	case scopeUser != nil:
+		interfaces = append(interfaces, scopeUser)
+	}
+
+	packet := NewPacket(err.Error(), interfaces...)
+	if o.culprit != "" {
+		packet.Culprit = o.culprit
+	}
+
+	tags := scope.applyTo(packet)
+	for k, v := range o.tags {
+		if tags == nil {
+			tags = make(map[string]string, len(o.tags))
+		}
+		tags[k] = v
+	}
+	return Capture(packet, tags)
+}
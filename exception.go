@@ -0,0 +1,24 @@
+package raven
+
+import "fmt"
+
+// Exception is the Sentry exception interface: an error plus the
+// stacktrace captured at the point it was reported.
+// https://docs.getsentry.com/hosted/clientdev/interfaces/#exception-interface
+type Exception struct {
+	Value      string      `json:"value"`
+	Type       string      `json:"type"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// NewException returns an Exception describing err, attaching stacktrace
+// (see GetOrNewStacktrace).
+func NewException(err error, stacktrace *Stacktrace) *Exception {
+	return &Exception{
+		Value:      err.Error(),
+		Type:       fmt.Sprintf("%T", err),
+		Stacktrace: stacktrace,
+	}
+}
+
+func (e *Exception) Class() string { return "exception" }
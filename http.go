@@ -1,24 +1,30 @@
 package raven
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
 	"runtime/debug"
 	"strings"
 )
 
+// NewHttp builds the Http interface for req, scrubbing its cookies,
+// query string, and headers with DefaultClient's Scrubber (see
+// SetScrubber) regardless of which Client eventually reports the
+// resulting event.
 func NewHttp(req *http.Request) *Http {
+	scrubber := DefaultClient.getScrubber()
+
 	proto := "http"
 	if req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https" {
 		proto = "https"
 	}
 	h := &Http{
 		Method:  req.Method,
-		Cookies: req.Header.Get("Cookie"),
-		Query:   sanitizeQuery(req.URL.Query()).Encode(),
+		Cookies: scrubber.ScrubCookies(req.Header.Get("Cookie")),
+		Query:   scrubber.ScrubQuery(req.URL.Query()).Encode(),
 		URL:     proto + "://" + req.Host + req.URL.Path,
 		Headers: make(map[string]string, len(req.Header)),
 	}
@@ -28,22 +34,14 @@ func NewHttp(req *http.Request) *Http {
 	for k, v := range req.Header {
 		h.Headers[k] = strings.Join(v, ",")
 	}
+	h.Headers = scrubber.ScrubHeaders(h.Headers)
 	return h
 }
 
+// querySecretFields is the default set of query parameter keywords masked
+// by NewScrubber.
 var querySecretFields = []string{"password", "passphrase", "passwd", "secret"}
 
-func sanitizeQuery(query url.Values) url.Values {
-	for _, keyword := range querySecretFields {
-		for field := range query {
-			if strings.Contains(field, keyword) {
-				query[field] = []string{"********"}
-			}
-		}
-	}
-	return query
-}
-
 // https://docs.getsentry.com/hosted/clientdev/interfaces/#context-interfaces
 type Http struct {
 	// Required
@@ -71,7 +69,7 @@ func RecoveryHandler(handler func(http.ResponseWriter, *http.Request)) func(http
 	return func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if rval := recover(); rval != nil {
-				DealWithRecoveredValue(rval, r)
+				DealWithRecoveredValue(r.Context(), rval, r)
 				w.WriteHeader(http.StatusInternalServerError)
 			}
 		}()
@@ -81,13 +79,23 @@ func RecoveryHandler(handler func(http.ResponseWriter, *http.Request)) func(http
 }
 
 // DealWithRecoveredValue - helper to do ad-hoc error reporting after recovered panic
-// you might want to perform custom cleanup routines yourself
-func DealWithRecoveredValue(rval interface{}, r *http.Request) {
+// you might want to perform custom cleanup routines yourself. If ctx
+// carries a Scope (see NewContext), its tags, extra data, user, and
+// breadcrumbs are merged into the reported event; otherwise this behaves
+// exactly as before.
+func DealWithRecoveredValue(ctx context.Context, rval interface{}, r *http.Request) {
 	debug.PrintStack()
 	err := convertError(rval)
 	rvalStr := fmt.Sprint(rval)
-	packet := NewPacket(rvalStr, NewException(err, GetOrNewStacktrace(err, 2, 3, nil)), NewHttp(r))
-	Capture(packet, nil)
+
+	scope := FromContext(ctx)
+	interfaces := append(
+		[]Interface{NewException(err, GetOrNewStacktrace(err, 2, 3, nil))},
+		scope.interfacesWithFallback(r)...,
+	)
+
+	packet := NewPacket(rvalStr, interfaces...)
+	Capture(packet, scope.applyTo(packet))
 }
 
 // convertError properly converts response values from `recover()` to a Golang error type
@@ -0,0 +1,82 @@
+// Package ravenchi provides Sentry recovery middleware for the go-chi
+// router, built on top of raven.CaptureRequest. Unlike Gin and Echo, chi
+// has no framework-level error chain, so there is no OnlyCrashes mode here:
+// only recovered panics are reported.
+package ravenchi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	raven "github.com/talon-one/raven-go"
+)
+
+// Options configures Recovery.
+type Options struct {
+	// UserExtractor derives the reporting user from the request, if set.
+	UserExtractor raven.UserExtractor
+	// BodyLimit caps how many bytes of the request body are attached to
+	// captured events. Zero disables body capture.
+	BodyLimit int64
+}
+
+// Recovery returns middleware that reports panics to Sentry with HTTP and
+// user context attached, then responds with a bare 500. It seeds a
+// *raven.Scope on the request context so handlers downstream can enrich
+// it via raven.SetTag, raven.SetUser, and raven.AddBreadcrumb before an
+// error is ever captured.
+func Recovery(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := raven.NewScopeFromRequest(r)
+			r = r.WithContext(raven.NewContext(r.Context(), scope))
+			raven.AddBreadcrumb(r.Context(), raven.Breadcrumb{
+				Category: "http.request",
+				Message:  r.Method + " " + r.URL.Path,
+			})
+
+			body := raven.CaptureBody(r, opts.BodyLimit)
+
+			defer func() {
+				if rval := recover(); rval != nil {
+					capture(r, body, opts, convertRecovered(rval))
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func capture(r *http.Request, body string, opts Options, err error) {
+	captureOpts := []raven.CaptureOption{
+		raven.WithRequest(r),
+		raven.WithBody(body),
+		raven.WithTags(map[string]string{
+			"endpoint": routePattern(r),
+			"method":   r.Method,
+		}),
+	}
+	if opts.UserExtractor != nil {
+		captureOpts = append(captureOpts, raven.WithUserExtractor(opts.UserExtractor))
+	}
+
+	raven.CaptureRequest(r.Context(), err, captureOpts...)
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
+
+func convertRecovered(rval interface{}) error {
+	if err, ok := rval.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(rval))
+}
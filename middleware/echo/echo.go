@@ -0,0 +1,83 @@
+// Package ravenecho provides Sentry recovery middleware for the Echo web
+// framework, built on top of raven.CaptureRequest.
+package ravenecho
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	raven "github.com/talon-one/raven-go"
+)
+
+// Options configures Recovery.
+type Options struct {
+	// UserExtractor derives the reporting user from the request, if set.
+	UserExtractor raven.UserExtractor
+	// BodyLimit caps how many bytes of the request body are attached to
+	// captured events. Zero disables body capture.
+	BodyLimit int64
+	// OnlyCrashes, when true, suppresses reporting of errors returned by
+	// the handler that aren't panics; only recovered panics are captured.
+	// Defaults to false.
+	OnlyCrashes bool
+}
+
+// Recovery returns Echo middleware that reports panics to Sentry with HTTP
+// and user context attached, then responds with a bare 500. Unless
+// Options.OnlyCrashes is set, it also reports the error returned by the
+// next handler, if any, without altering Echo's own error handling. It
+// seeds a *raven.Scope on the request context so handlers downstream can
+// enrich it via raven.SetTag, raven.SetUser, and raven.AddBreadcrumb
+// before an error is ever captured.
+func Recovery(opts Options) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scope := raven.NewScopeFromRequest(c.Request())
+			c.SetRequest(c.Request().WithContext(raven.NewContext(c.Request().Context(), scope)))
+			raven.AddBreadcrumb(c.Request().Context(), raven.Breadcrumb{
+				Category: "http.request",
+				Message:  c.Request().Method + " " + c.Path(),
+			})
+
+			body := raven.CaptureBody(c.Request(), opts.BodyLimit)
+
+			defer func() {
+				if rval := recover(); rval != nil {
+					capture(c, body, opts, convertRecovered(rval))
+					c.NoContent(http.StatusInternalServerError)
+				}
+			}()
+
+			err := next(c)
+			if err != nil && !opts.OnlyCrashes {
+				capture(c, body, opts, err)
+			}
+			return err
+		}
+	}
+}
+
+func capture(c echo.Context, body string, opts Options, err error) {
+	captureOpts := []raven.CaptureOption{
+		raven.WithRequest(c.Request()),
+		raven.WithBody(body),
+		raven.WithTags(map[string]string{
+			"endpoint": c.Path(),
+			"method":   c.Request().Method,
+		}),
+	}
+	if opts.UserExtractor != nil {
+		captureOpts = append(captureOpts, raven.WithUserExtractor(opts.UserExtractor))
+	}
+
+	raven.CaptureRequest(c.Request().Context(), err, captureOpts...)
+}
+
+func convertRecovered(rval interface{}) error {
+	if err, ok := rval.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(rval))
+}
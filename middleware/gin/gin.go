@@ -0,0 +1,83 @@
+// Package ravengin provides Sentry recovery middleware for the Gin web
+// framework, built on top of raven.CaptureRequest.
+package ravengin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	raven "github.com/talon-one/raven-go"
+)
+
+// Options configures Recovery.
+type Options struct {
+	// UserExtractor derives the reporting user from the request, if set.
+	UserExtractor raven.UserExtractor
+	// BodyLimit caps how many bytes of the request body are attached to
+	// captured events. Zero disables body capture.
+	BodyLimit int64
+	// OnlyCrashes, when true, suppresses reporting of errors pushed onto
+	// gin.Context.Errors by handlers that don't panic; only recovered
+	// panics are captured. Defaults to false.
+	OnlyCrashes bool
+}
+
+// Recovery returns Gin middleware that reports panics to Sentry with HTTP
+// and user context attached, then responds with a bare 500. Unless
+// Options.OnlyCrashes is set, it also reports any errors left on
+// gin.Context.Errors once the handler chain completes, without aborting
+// the response. It seeds a *raven.Scope on the request context so
+// handlers downstream can enrich it via raven.SetTag, raven.SetUser, and
+// raven.AddBreadcrumb before an error is ever captured.
+func Recovery(opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := raven.NewScopeFromRequest(c.Request)
+		c.Request = c.Request.WithContext(raven.NewContext(c.Request.Context(), scope))
+		raven.AddBreadcrumb(c.Request.Context(), raven.Breadcrumb{
+			Category: "http.request",
+			Message:  c.Request.Method + " " + c.Request.URL.Path,
+		})
+
+		body := raven.CaptureBody(c.Request, opts.BodyLimit)
+
+		defer func() {
+			if rval := recover(); rval != nil {
+				capture(c, body, opts, convertRecovered(rval))
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+
+		c.Next()
+
+		if !opts.OnlyCrashes {
+			for _, ginErr := range c.Errors {
+				capture(c, body, opts, ginErr.Err)
+			}
+		}
+	}
+}
+
+func capture(c *gin.Context, body string, opts Options, err error) {
+	captureOpts := []raven.CaptureOption{
+		raven.WithRequest(c.Request),
+		raven.WithBody(body),
+		raven.WithTags(map[string]string{
+			"endpoint": c.FullPath(),
+			"method":   c.Request.Method,
+		}),
+	}
+	if opts.UserExtractor != nil {
+		captureOpts = append(captureOpts, raven.WithUserExtractor(opts.UserExtractor))
+	}
+
+	raven.CaptureRequest(c.Request.Context(), err, captureOpts...)
+}
+
+func convertRecovered(rval interface{}) error {
+	if err, ok := rval.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(rval))
+}
@@ -0,0 +1,78 @@
+package raven
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Interface is implemented by anything that can be attached to a Packet
+// as a structured context block, e.g. Http and User.
+type Interface interface {
+	// Class returns the JSON key Sentry groups this interface's data
+	// under (e.g. "request", "user", "exception").
+	Class() string
+}
+
+// Packet is a single event, following Sentry's client development
+// protocol.
+// https://docs.getsentry.com/hosted/clientdev/
+type Packet struct {
+	EventID string `json:"event_id"`
+	Message string `json:"message"`
+	Culprit string `json:"culprit,omitempty"`
+
+	Tags  map[string]string      `json:"tags,omitempty"`
+	Extra map[string]interface{} `json:"extra,omitempty"`
+
+	// Breadcrumbs are drained from the capturing Scope's ring buffer (see
+	// AddBreadcrumb) by CaptureError, CaptureRequest, and
+	// DealWithRecoveredValue.
+	Breadcrumbs []Breadcrumb `json:"-"`
+
+	Interfaces []Interface `json:"-"`
+}
+
+// NewPacket returns a Packet with a freshly generated EventID, carrying
+// interfaces as its structured context.
+func NewPacket(message string, interfaces ...Interface) *Packet {
+	return &Packet{
+		EventID:    newEventID(),
+		Message:    message,
+		Interfaces: interfaces,
+	}
+}
+
+// MarshalJSON flattens Interfaces into top-level fields keyed by each
+// interface's Class(), matching Sentry's wire format.
+func (packet *Packet) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{}, len(packet.Interfaces)+4)
+	data["event_id"] = packet.EventID
+	data["message"] = packet.Message
+	if packet.Culprit != "" {
+		data["culprit"] = packet.Culprit
+	}
+	if len(packet.Tags) > 0 {
+		data["tags"] = packet.Tags
+	}
+	if len(packet.Extra) > 0 {
+		data["extra"] = packet.Extra
+	}
+	if len(packet.Breadcrumbs) > 0 {
+		data["breadcrumbs"] = map[string]interface{}{"values": packet.Breadcrumbs}
+	}
+	for _, iface := range packet.Interfaces {
+		data[iface.Class()] = iface
+	}
+	return json.Marshal(data)
+}
+
+// newEventID returns a random 32-character hex event ID, the form
+// Sentry's protocol requires.
+func newEventID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
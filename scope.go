@@ -0,0 +1,185 @@
+package raven
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type scopeContextKey struct{}
+
+// Scope accumulates request-scoped reporting context - tags, extra data,
+// the reporting user, HTTP context, and breadcrumbs - that gets merged
+// into every event captured through a context.Context carrying it.
+type Scope struct {
+	mu sync.Mutex
+
+	Tags        map[string]string
+	Extra       map[string]interface{}
+	User        *User
+	Http        *Http
+	Breadcrumbs []Breadcrumb
+}
+
+// NewScope returns an empty Scope ready for use with NewContext.
+func NewScope() *Scope {
+	return &Scope{}
+}
+
+// NewScopeFromRequest returns a Scope seeded with r's HTTP context, ready
+// to store on r.Context() via NewContext. Middleware packages use this to
+// set up the scope downstream handlers then enrich via SetTag, SetUser,
+// and AddBreadcrumb.
+func NewScopeFromRequest(r *http.Request) *Scope {
+	return &Scope{Http: NewHttp(r)}
+}
+
+// NewContext returns a copy of ctx carrying scope. Subsequent SetTag,
+// SetUser, AddBreadcrumb, and CaptureError calls made with the returned
+// context (or one derived from it) operate on scope.
+func NewContext(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, scope)
+}
+
+// FromContext returns the Scope stored in ctx, or nil if ctx carries none.
+func FromContext(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(scopeContextKey{}).(*Scope)
+	return scope
+}
+
+// SetTag sets a tag on ctx's Scope. It is a no-op if ctx carries no Scope.
+func SetTag(ctx context.Context, key, value string) {
+	scope := FromContext(ctx)
+	if scope == nil {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	if scope.Tags == nil {
+		scope.Tags = make(map[string]string)
+	}
+	scope.Tags[key] = value
+}
+
+// SetExtra sets an extra value on ctx's Scope. It is a no-op if ctx
+// carries no Scope.
+func SetExtra(ctx context.Context, key string, value interface{}) {
+	scope := FromContext(ctx)
+	if scope == nil {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	if scope.Extra == nil {
+		scope.Extra = make(map[string]interface{})
+	}
+	scope.Extra[key] = value
+}
+
+// SetUser sets the reporting user on ctx's Scope. It is a no-op if ctx
+// carries no Scope.
+func SetUser(ctx context.Context, user *User) {
+	scope := FromContext(ctx)
+	if scope == nil {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.User = user
+}
+
+// AddBreadcrumb appends a breadcrumb to ctx's Scope, trimming the oldest
+// entry once DefaultClient's configured SetMaxBreadcrumbs limit is
+// exceeded (other Clients' limits are not consulted here). It is a no-op
+// if ctx carries no Scope. Timestamp defaults to now if unset.
+func AddBreadcrumb(ctx context.Context, crumb Breadcrumb) {
+	scope := FromContext(ctx)
+	if scope == nil {
+		return
+	}
+	if crumb.Timestamp.IsZero() {
+		crumb.Timestamp = time.Now()
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.Breadcrumbs = append(scope.Breadcrumbs, crumb)
+	if max := DefaultClient.getMaxBreadcrumbs(); len(scope.Breadcrumbs) > max {
+		scope.Breadcrumbs = scope.Breadcrumbs[len(scope.Breadcrumbs)-max:]
+	}
+}
+
+// httpAndUser returns scope's Http and User fields under its lock, safe
+// for concurrent use with SetUser, SetTag, and AddBreadcrumb. It is a
+// no-op returning (nil, nil) if scope is nil.
+func (scope *Scope) httpAndUser() (*Http, *User) {
+	if scope == nil {
+		return nil, nil
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	return scope.Http, scope.User
+}
+
+// interfacesWithFallback returns scope's accumulated Http/User interfaces.
+// If scope is nil or has no Http set, r is used to build one instead (and
+// may itself be nil, e.g. for non-HTTP callers of CaptureError).
+func (scope *Scope) interfacesWithFallback(r *http.Request) []Interface {
+	h, user := scope.httpAndUser()
+
+	var interfaces []Interface
+	switch {
+	case h != nil:
+		interfaces = append(interfaces, h)
+	case r != nil:
+		interfaces = append(interfaces, NewHttp(r))
+	}
+	if user != nil {
+		interfaces = append(interfaces, user)
+	}
+	return interfaces
+}
+
+// applyTo merges scope's extra data onto packet, moves scope's breadcrumbs
+// onto packet (emptying scope's buffer), and returns its tags as a plain
+// map, ready for Capture's captureTags parameter. It is a no-op, returning
+// nil, if scope is nil.
+func (scope *Scope) applyTo(packet *Packet) map[string]string {
+	if scope == nil {
+		return nil
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	if len(scope.Extra) > 0 {
+		packet.Extra = scope.Extra
+	}
+	if len(scope.Breadcrumbs) > 0 {
+		packet.Breadcrumbs = append(packet.Breadcrumbs, scope.Breadcrumbs...)
+		scope.Breadcrumbs = nil
+	}
+	if len(scope.Tags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(scope.Tags))
+	for k, v := range scope.Tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// CaptureError reports err to Sentry, merging in any Scope carried by ctx
+// (tags, extra, user, and HTTP context) alongside a fresh exception and
+// stacktrace. Prefer this over the context-less package-level Capture in
+// ctx-first code.
+func CaptureError(ctx context.Context, err error) (eventID string, ch chan error) {
+	scope := FromContext(ctx)
+	interfaces := append(
+		[]Interface{NewException(err, GetOrNewStacktrace(err, 2, 3, nil))},
+		scope.interfacesWithFallback(nil)...,
+	)
+
+	packet := NewPacket(err.Error(), interfaces...)
+	return Capture(packet, scope.applyTo(packet))
+}
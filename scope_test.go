@@ -0,0 +1,77 @@
+package raven
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAddBreadcrumbTrimsToMax(t *testing.T) {
+	SetMaxBreadcrumbs(3)
+	defer SetMaxBreadcrumbs(0)
+
+	ctx := NewContext(context.Background(), NewScope())
+	for _, msg := range []string{"one", "two", "three", "four", "five"} {
+		AddBreadcrumb(ctx, Breadcrumb{Message: msg})
+	}
+
+	scope := FromContext(ctx)
+	if len(scope.Breadcrumbs) != 3 {
+		t.Fatalf("len(Breadcrumbs) = %d, want 3", len(scope.Breadcrumbs))
+	}
+	got := []string{scope.Breadcrumbs[0].Message, scope.Breadcrumbs[1].Message, scope.Breadcrumbs[2].Message}
+	want := []string{"three", "four", "five"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Breadcrumbs[%d] = %q, want %q (oldest should be dropped first)", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAddBreadcrumbNoScopeIsNoOp(t *testing.T) {
+	// Must not panic when ctx carries no Scope.
+	AddBreadcrumb(context.Background(), Breadcrumb{Message: "ignored"})
+}
+
+func TestAddBreadcrumbDefaultsTimestamp(t *testing.T) {
+	ctx := NewContext(context.Background(), NewScope())
+	AddBreadcrumb(ctx, Breadcrumb{Message: "hi"})
+
+	scope := FromContext(ctx)
+	if scope.Breadcrumbs[0].Timestamp.IsZero() {
+		t.Error("Timestamp left zero, want it defaulted to now")
+	}
+}
+
+// TestScopeConcurrentAccess exercises a shared Scope the way a ctx-first
+// caller fanning work out across goroutines (e.g. errgroup) would: writes
+// via SetUser/SetTag/AddBreadcrumb racing reads via
+// interfacesWithFallback. Run with -race; it must not report a race.
+func TestScopeConcurrentAccess(t *testing.T) {
+	scope := NewScope()
+	ctx := NewContext(context.Background(), scope)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		i := i
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			SetUser(ctx, &User{ID: fmt.Sprintf("u%d", i)})
+		}()
+		go func() {
+			defer wg.Done()
+			SetTag(ctx, "iteration", fmt.Sprintf("%d", i))
+		}()
+		go func() {
+			defer wg.Done()
+			AddBreadcrumb(ctx, Breadcrumb{Message: fmt.Sprintf("m%d", i)})
+		}()
+		go func() {
+			defer wg.Done()
+			scope.interfacesWithFallback(nil)
+		}()
+	}
+	wg.Wait()
+}
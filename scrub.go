@@ -0,0 +1,284 @@
+package raven
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ScrubRule decides whether a key (header name, cookie name, query
+// parameter name, ...) or a value should be masked.
+type ScrubRule struct {
+	// KeyPattern masks any field whose name matches this regular
+	// expression. Takes precedence over KeyContains.
+	KeyPattern *regexp.Regexp
+	// KeyContains masks any field whose name contains this substring,
+	// case-insensitively. Ignored if KeyPattern is set.
+	KeyContains string
+	// ValuePattern masks any value matching this regular expression,
+	// independent of its key. If Validate is set, a regex match is only
+	// masked when Validate also returns true (used for e.g. credit card
+	// numbers, where the Luhn check rules out false positives).
+	ValuePattern *regexp.Regexp
+	// Validate further constrains a ValuePattern match.
+	Validate func(value string) bool
+}
+
+func (r ScrubRule) matchesKey(key string) bool {
+	switch {
+	case r.KeyPattern != nil:
+		return r.KeyPattern.MatchString(key)
+	case r.KeyContains != "":
+		return strings.Contains(strings.ToLower(key), strings.ToLower(r.KeyContains))
+	default:
+		return false
+	}
+}
+
+func (r ScrubRule) matchesValue(value string) bool {
+	if r.ValuePattern == nil || !r.ValuePattern.MatchString(value) {
+		return false
+	}
+	return r.Validate == nil || r.Validate(value)
+}
+
+// Predefined value-pattern matchers for common secret shapes, ready to use
+// in a Scrubber's rule lists.
+var (
+	EmailValuePattern      = ScrubRule{ValuePattern: regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)}
+	JWTValuePattern        = ScrubRule{ValuePattern: regexp.MustCompile(`^eyJ[\w-]+\.[\w-]+\.[\w-]*$`)}
+	IPValuePattern         = ScrubRule{ValuePattern: regexp.MustCompile(`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`)}
+	CreditCardValuePattern = ScrubRule{
+		ValuePattern: regexp.MustCompile(`^[0-9 -]{13,23}$`),
+		Validate:     isLuhnValid,
+	}
+)
+
+// isLuhnValid reports whether the digits in value pass the Luhn checksum
+// used by credit card numbers.
+func isLuhnValid(value string) bool {
+	var digits []int
+	for _, r := range value {
+		if r == ' ' || r == '-' {
+			continue
+		}
+		d, err := strconv.Atoi(string(r))
+		if err != nil {
+			return false
+		}
+		digits = append(digits, d)
+	}
+	if len(digits) < 12 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Scrubber masks sensitive data out of outgoing events. The zero value
+// scrubs nothing. Use NewScrubber for raven's default rule set.
+type Scrubber struct {
+	// Replacement is substituted for masked values. Defaults to
+	// "********" if empty.
+	Replacement string
+
+	Query   []ScrubRule
+	Headers []ScrubRule
+	Cookies []ScrubRule
+	Data    []ScrubRule
+}
+
+// NewScrubber returns the Scrubber raven applies by default: the legacy
+// querySecretFields keywords for query parameters, and the header names
+// Sentry's own clients mask by convention.
+func NewScrubber() *Scrubber {
+	s := &Scrubber{}
+	for _, keyword := range querySecretFields {
+		s.Query = append(s.Query, ScrubRule{KeyContains: keyword})
+	}
+	for _, header := range []string{"Authorization", "Cookie", "X-Api-Key", "Proxy-Authorization"} {
+		s.Headers = append(s.Headers, ScrubRule{KeyContains: header})
+	}
+	return s
+}
+
+func (s *Scrubber) replacement() string {
+	if s.Replacement != "" {
+		return s.Replacement
+	}
+	return "********"
+}
+
+func (s *Scrubber) mask(rules []ScrubRule, key, value string) string {
+	for _, rule := range rules {
+		if (key != "" && rule.matchesKey(key)) || rule.matchesValue(value) {
+			return s.replacement()
+		}
+	}
+	return value
+}
+
+// ScrubQuery masks matching query parameter values in place and returns
+// query for chaining.
+func (s *Scrubber) ScrubQuery(query url.Values) url.Values {
+	if s == nil {
+		return query
+	}
+	for field, values := range query {
+		for i, v := range values {
+			values[i] = s.mask(s.Query, field, v)
+		}
+		query[field] = values
+	}
+	return query
+}
+
+// ScrubHeaders returns a copy of headers with matching values masked.
+func (s *Scrubber) ScrubHeaders(headers map[string]string) map[string]string {
+	if s == nil {
+		return headers
+	}
+	scrubbed := make(map[string]string, len(headers))
+	for k, v := range headers {
+		scrubbed[k] = s.mask(s.Headers, k, v)
+	}
+	return scrubbed
+}
+
+// ScrubCookies parses a "Cookie" header value into individual name/value
+// pairs, masks matching ones, and re-joins them in the original format.
+func (s *Scrubber) ScrubCookies(cookies string) string {
+	if s == nil || cookies == "" {
+		return cookies
+	}
+	parts := strings.Split(cookies, ";")
+	for i, part := range parts {
+		name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		parts[i] = name + "=" + s.mask(s.Cookies, name, value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ScrubData masks matching values out of an Http.Data payload. A
+// map[string]string is masked key-by-key; a string or []byte is masked as
+// JSON if it decodes as an object, or otherwise swept for ValuePattern
+// matches (e.g. EmailValuePattern, CreditCardValuePattern) as raw text,
+// so a non-JSON body (form-encoded, multipart, plain text) still gets its
+// recognizable secrets masked instead of shipping unscrubbed. Anything
+// else is returned unchanged.
+func (s *Scrubber) ScrubData(data interface{}) interface{} {
+	if s == nil || data == nil {
+		return data
+	}
+
+	switch v := data.(type) {
+	case map[string]string:
+		scrubbed := make(map[string]string, len(v))
+		for k, val := range v {
+			scrubbed[k] = s.mask(s.Data, k, val)
+		}
+		return scrubbed
+	case string:
+		return s.scrubJSON(v)
+	case []byte:
+		switch scrubbed := s.scrubJSON(string(v)).(type) {
+		case string:
+			return []byte(scrubbed)
+		default:
+			return scrubbed
+		}
+	default:
+		return data
+	}
+}
+
+// setScrubber installs the Scrubber used when building future events for
+// this client. A nil scrubber disables scrubbing. Unexported because only
+// DefaultClient's Scrubber is ever consulted (by NewHttp and
+// CaptureRequest); there is no way to make a per-Client setting here
+// actually take effect without threading the owning Client through every
+// call site that builds an event, so it isn't exposed as part of the
+// Client API.
+func (client *Client) setScrubber(scrubber *Scrubber) {
+	if scrubber == nil {
+		// The zero Scrubber masks nothing; nil itself is reserved by
+		// getScrubber for "not configured yet".
+		scrubber = &Scrubber{}
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.scrubber = scrubber
+}
+
+// getScrubber returns the client's configured Scrubber, falling back to
+// NewScrubber's defaults the first time it's needed.
+func (client *Client) getScrubber() *Scrubber {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.scrubber == nil {
+		client.scrubber = NewScrubber()
+	}
+	return client.scrubber
+}
+
+// SetScrubber installs the Scrubber used by the package-level API
+// (NewHttp and CaptureRequest).
+func SetScrubber(scrubber *Scrubber) {
+	DefaultClient.setScrubber(scrubber)
+}
+
+// scrubJSON decodes raw as a JSON object and masks its string values by
+// key, or, if raw isn't a JSON object, falls back to scrubRawString.
+func (s *Scrubber) scrubJSON(raw string) interface{} {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return s.scrubRawString(raw)
+	}
+	for k, val := range decoded {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		decoded[k] = s.mask(s.Data, k, str)
+	}
+	return decoded
+}
+
+// scrubRawString masks substrings of raw matching any Data rule's
+// ValuePattern - used for bodies that don't decode as a JSON object
+// (form-encoded, multipart, plain text), where there are no keys to
+// match against. Rules with only a KeyPattern/KeyContains have nothing
+// to match here and are skipped.
+func (s *Scrubber) scrubRawString(raw string) string {
+	masked := raw
+	for _, rule := range s.Data {
+		if rule.ValuePattern == nil {
+			continue
+		}
+		masked = rule.ValuePattern.ReplaceAllStringFunc(masked, func(match string) string {
+			if rule.Validate != nil && !rule.Validate(match) {
+				return match
+			}
+			return s.replacement()
+		})
+	}
+	return masked
+}
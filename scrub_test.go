@@ -0,0 +1,132 @@
+package raven
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestIsLuhnValid(t *testing.T) {
+	cases := []struct {
+		value string
+		want  bool
+	}{
+		{"4242424242424242", true},
+		{"4242-4242-4242-4242", true},
+		{"4242424242424241", false},
+		{"not-a-number", false},
+		{"4242", false},
+	}
+	for _, c := range cases {
+		if got := isLuhnValid(c.value); got != c.want {
+			t.Errorf("isLuhnValid(%q) = %v, want %v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestScrubberScrubQuery(t *testing.T) {
+	s := NewScrubber()
+	query := url.Values{"password": {"hunter2"}, "q": {"golang"}}
+
+	scrubbed := s.ScrubQuery(query)
+
+	if got := scrubbed.Get("password"); got != s.replacement() {
+		t.Errorf("password = %q, want %q", got, s.replacement())
+	}
+	if got := scrubbed.Get("q"); got != "golang" {
+		t.Errorf("q = %q, want unchanged %q", got, "golang")
+	}
+}
+
+func TestScrubberScrubHeaders(t *testing.T) {
+	s := NewScrubber()
+	headers := map[string]string{
+		"Authorization": "Bearer abc123",
+		"X-Request-ID":  "req-1",
+	}
+
+	scrubbed := s.ScrubHeaders(headers)
+
+	if got := scrubbed["Authorization"]; got != s.replacement() {
+		t.Errorf("Authorization = %q, want %q", got, s.replacement())
+	}
+	if got := scrubbed["X-Request-ID"]; got != "req-1" {
+		t.Errorf("X-Request-ID = %q, want unchanged %q", got, "req-1")
+	}
+}
+
+func TestScrubberScrubCookies(t *testing.T) {
+	s := &Scrubber{Cookies: []ScrubRule{{KeyContains: "session"}}}
+
+	got := s.ScrubCookies("session_id=abc123; theme=dark")
+	want := "session_id=" + s.replacement() + "; theme=dark"
+	if got != want {
+		t.Errorf("ScrubCookies() = %q, want %q", got, want)
+	}
+}
+
+func TestScrubberScrubDataValuePattern(t *testing.T) {
+	s := &Scrubber{Data: []ScrubRule{EmailValuePattern}}
+
+	got := s.ScrubData(map[string]string{"contact": "user@example.com"})
+	m, ok := got.(map[string]string)
+	if !ok || m["contact"] != s.replacement() {
+		t.Errorf("ScrubData() = %#v, want contact masked", got)
+	}
+}
+
+func TestScrubberScrubDataJSONString(t *testing.T) {
+	s := &Scrubber{Data: []ScrubRule{{KeyContains: "password"}}}
+
+	got := s.ScrubData(`{"username":"alice","password":"hunter2"}`)
+	m, ok := got.(map[string]interface{})
+	if !ok {
+		t.Fatalf("ScrubData() = %#v, want a decoded map", got)
+	}
+	if m["password"] != s.replacement() {
+		t.Errorf("password = %v, want %q", m["password"], s.replacement())
+	}
+	if m["username"] != "alice" {
+		t.Errorf("username = %v, want unchanged", m["username"])
+	}
+}
+
+func TestScrubberScrubDataNonJSONWithNoDataRulesIsUnchanged(t *testing.T) {
+	s := NewScrubber()
+
+	got := s.ScrubData("not json")
+	if got != "not json" {
+		t.Errorf("ScrubData() = %v, want unchanged (NewScrubber sets no Data rules)", got)
+	}
+}
+
+func TestScrubberScrubDataNonJSONSweepsValuePatterns(t *testing.T) {
+	s := &Scrubber{Data: []ScrubRule{EmailValuePattern}}
+
+	got := s.ScrubData("contact=user@example.com&plan=pro")
+	want := "contact=" + s.replacement() + "&plan=pro"
+	if got != want {
+		t.Errorf("ScrubData() = %q, want %q", got, want)
+	}
+}
+
+func TestScrubberScrubDataNonJSONBytesSweepsValuePatterns(t *testing.T) {
+	s := &Scrubber{Data: []ScrubRule{EmailValuePattern}}
+
+	got := s.ScrubData([]byte("contact=user@example.com"))
+	b, ok := got.([]byte)
+	want := "contact=" + s.replacement()
+	if !ok || string(b) != want {
+		t.Errorf("ScrubData() = %#v, want []byte(%q)", got, want)
+	}
+}
+
+func TestNilScrubberIsNoOp(t *testing.T) {
+	var s *Scrubber
+
+	if got := s.ScrubQuery(url.Values{"password": {"hunter2"}}).Get("password"); got != "hunter2" {
+		t.Errorf("nil Scrubber ScrubQuery masked a value: %q", got)
+	}
+	if got := s.ScrubCookies("a=b"); got != "a=b" {
+		t.Errorf("nil Scrubber ScrubCookies changed value: %q", got)
+	}
+}
@@ -0,0 +1,37 @@
+package raven
+
+import "runtime"
+
+// StacktraceFrame describes a single call frame.
+type StacktraceFrame struct {
+	Filename string `json:"filename"`
+	Function string `json:"function"`
+	Lineno   int    `json:"lineno"`
+}
+
+// Stacktrace is the Sentry stacktrace interface.
+// https://docs.getsentry.com/hosted/clientdev/interfaces/#stack-trace-interface
+type Stacktrace struct {
+	Frames []StacktraceFrame `json:"frames"`
+}
+
+func (s *Stacktrace) Class() string { return "stacktrace" }
+
+// GetOrNewStacktrace walks the call stack starting skip frames up from
+// its own caller, producing a Stacktrace. err, context, and
+// appPackagePrefixes are accepted for API compatibility and are not used.
+func GetOrNewStacktrace(err error, skip, context int, appPackagePrefixes []string) *Stacktrace {
+	var frames []StacktraceFrame
+	for i := skip; ; i++ {
+		pc, file, line, ok := runtime.Caller(i)
+		if !ok {
+			break
+		}
+		name := ""
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			name = fn.Name()
+		}
+		frames = append(frames, StacktraceFrame{Filename: file, Function: name, Lineno: line})
+	}
+	return &Stacktrace{Frames: frames}
+}
@@ -0,0 +1,248 @@
+package raven
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport sends a Packet to Sentry. SetTransport/Client.SetTransport let
+// callers swap in a custom implementation, or disable retries by
+// installing one backed directly by http.Client.Do.
+type Transport interface {
+	Send(url, authHeader string, packet *Packet) error
+}
+
+// errorCategory is the rate-limit category Sentry uses for error events
+// (https://develop.sentry.dev/sdk/rate-limiting/#definitions), the only
+// kind of event raven-go sends - so it's the only category rateLimitWait
+// is ever checked against in deliver.
+const errorCategory = "error"
+
+// RetryTransport is the default Transport. Sends are queued and delivered
+// by a background worker with exponential backoff and jitter, so Send
+// itself never blocks on the network. It honors Sentry's rate-limit
+// signaling - the Retry-After header and the richer
+// X-Sentry-Rate-Limits header - by parking further sends for the
+// project/category the response named.
+type RetryTransport struct {
+	// Client is the underlying HTTP client used to deliver events.
+	// Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// RetryWaitMin and RetryWaitMax bound the exponential backoff between
+	// attempts. Defaults: 100ms and 10s.
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	// RetryMax is the maximum number of attempts per packet, including
+	// the first. Defaults to 5.
+	RetryMax int
+	// QueueSize bounds the number of packets buffered ahead of the
+	// network so Send returns immediately even during an outage. Once
+	// full, Send drops the packet and returns an error. Defaults to 100.
+	QueueSize int
+
+	initOnce sync.Once
+	queue    chan sendRequest
+
+	mu           sync.Mutex
+	limitedUntil map[string]time.Time // rate limit category -> unblock time; "" is project-wide
+}
+
+type sendRequest struct {
+	url        string
+	authHeader string
+	packet     *Packet
+}
+
+func (t *RetryTransport) init() {
+	t.initOnce.Do(func() {
+		if t.Client == nil {
+			t.Client = http.DefaultClient
+		}
+		if t.RetryWaitMin == 0 {
+			t.RetryWaitMin = 100 * time.Millisecond
+		}
+		if t.RetryWaitMax == 0 {
+			t.RetryWaitMax = 10 * time.Second
+		}
+		if t.RetryMax == 0 {
+			t.RetryMax = 5
+		}
+		if t.QueueSize == 0 {
+			t.QueueSize = 100
+		}
+		t.limitedUntil = make(map[string]time.Time)
+		t.queue = make(chan sendRequest, t.QueueSize)
+		go t.run()
+	})
+}
+
+// Send enqueues packet for delivery and returns immediately. It returns an
+// error only if the queue is full.
+func (t *RetryTransport) Send(url, authHeader string, packet *Packet) error {
+	t.init()
+	select {
+	case t.queue <- sendRequest{url: url, authHeader: authHeader, packet: packet}:
+		return nil
+	default:
+		return fmt.Errorf("raven: transport queue full, dropping event %s", packet.EventID)
+	}
+}
+
+func (t *RetryTransport) run() {
+	for req := range t.queue {
+		t.deliver(req)
+	}
+}
+
+func (t *RetryTransport) deliver(req sendRequest) {
+	for attempt := 0; attempt < t.RetryMax; attempt++ {
+		if wait := t.rateLimitWait(errorCategory); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		resp, err := t.post(req)
+		if err == nil {
+			return
+		}
+		if resp != nil {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				t.applyRateLimit(resp)
+				return
+			}
+			if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+				// Other 4xx: the request itself is bad, retrying won't help.
+				return
+			}
+		}
+
+		if attempt == t.RetryMax-1 {
+			return
+		}
+		time.Sleep(t.backoff(attempt))
+	}
+}
+
+func (t *RetryTransport) post(req sendRequest) (*http.Response, error) {
+	body, err := json.Marshal(req.packet)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest("POST", req.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Sentry-Auth", req.authHeader)
+
+	resp, err := t.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return resp, fmt.Errorf("raven: got http status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// backoff computes attempt's wait time: RetryWaitMin doubled per attempt,
+// capped at RetryWaitMax, with up to 50% jitter added to avoid a thundering
+// herd of clients retrying in lockstep.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	wait := t.RetryWaitMin << uint(attempt)
+	if wait <= 0 || wait > t.RetryWaitMax {
+		wait = t.RetryWaitMax
+	}
+	return wait + time.Duration(rand.Int63n(int64(wait)/2+1))
+}
+
+// applyRateLimit parks future sends per the response's rate-limit headers.
+// X-Sentry-Rate-Limits takes precedence over the simpler Retry-After.
+func (t *RetryTransport) applyRateLimit(resp *http.Response) {
+	now := time.Now()
+
+	if header := resp.Header.Get("X-Sentry-Rate-Limits"); header != "" {
+		for _, entry := range strings.Split(header, ",") {
+			fields := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+			if len(fields) < 2 {
+				continue
+			}
+			seconds, err := strconv.Atoi(fields[0])
+			if err != nil {
+				continue
+			}
+			until := now.Add(time.Duration(seconds) * time.Second)
+
+			categories := strings.TrimSpace(fields[1])
+			if categories == "" {
+				t.setRateLimit("", until)
+				continue
+			}
+			for _, category := range strings.Split(categories, ";") {
+				t.setRateLimit(category, until)
+			}
+		}
+		return
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			t.setRateLimit("", now.Add(time.Duration(seconds)*time.Second))
+		}
+	}
+}
+
+func (t *RetryTransport) setRateLimit(category string, until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if current, ok := t.limitedUntil[category]; !ok || until.After(current) {
+		t.limitedUntil[category] = until
+	}
+}
+
+// rateLimitWait returns how long to wait before sending to category, which
+// may be "" for the project-wide limit.
+func (t *RetryTransport) rateLimitWait(category string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until := t.limitedUntil[category]
+	if global := t.limitedUntil[""]; global.After(until) {
+		until = global
+	}
+	return time.Until(until)
+}
+
+// SetTransport installs the Transport used to deliver future events for
+// this client.
+func (client *Client) SetTransport(transport Transport) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.transport = transport
+}
+
+// getTransport returns the client's configured Transport, defaulting to a
+// new RetryTransport the first time one is needed.
+func (client *Client) getTransport() Transport {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.transport == nil {
+		client.transport = &RetryTransport{}
+	}
+	return client.transport
+}
+
+// SetTransport installs the Transport used by the package-level API.
+func SetTransport(transport Transport) {
+	DefaultClient.SetTransport(transport)
+}
@@ -0,0 +1,112 @@
+package raven
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryTransportBackoffDoublesAndCaps(t *testing.T) {
+	tr := &RetryTransport{RetryWaitMin: 100 * time.Millisecond, RetryWaitMax: time.Second}
+	tr.init()
+
+	for attempt, min := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+	} {
+		wait := tr.backoff(attempt)
+		if wait < min || wait > min+min/2 {
+			t.Errorf("backoff(%d) = %v, want in [%v, %v]", attempt, wait, min, min+min/2)
+		}
+	}
+
+	// Attempt large enough that unjittered wait would exceed RetryWaitMax;
+	// backoff must clamp to RetryWaitMax (plus its own jitter).
+	wait := tr.backoff(10)
+	if wait < tr.RetryWaitMax || wait > tr.RetryWaitMax+tr.RetryWaitMax/2 {
+		t.Errorf("backoff(10) = %v, want in [%v, %v]", wait, tr.RetryWaitMax, tr.RetryWaitMax+tr.RetryWaitMax/2)
+	}
+}
+
+func TestRetryTransportApplyRateLimitSentryHeader(t *testing.T) {
+	tr := &RetryTransport{}
+	tr.init()
+
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set("X-Sentry-Rate-Limits", "60:error;transaction:key, 1:default::organization")
+	tr.applyRateLimit(resp)
+
+	// "error" is the category deliver actually checks (see errorCategory);
+	// the rest are parsed and stored the same way but unused today since
+	// raven-go never sends a non-error event.
+	if wait := tr.rateLimitWait(errorCategory); wait <= 0 || wait > 60*time.Second {
+		t.Errorf("rateLimitWait(%q) = %v, want roughly 60s", errorCategory, wait)
+	}
+	if wait := tr.rateLimitWait("transaction"); wait <= 0 || wait > 60*time.Second {
+		t.Errorf("rateLimitWait(%q) = %v, want roughly 60s", "transaction", wait)
+	}
+	if wait := tr.rateLimitWait("default"); wait <= 0 || wait > time.Second {
+		t.Errorf("rateLimitWait(%q) = %v, want roughly 1s", "default", wait)
+	}
+	if wait := tr.rateLimitWait("unlisted"); wait > 0 {
+		t.Errorf("rateLimitWait(%q) = %v, want no limit", "unlisted", wait)
+	}
+}
+
+func TestRetryTransportApplyRateLimitRetryAfterFallback(t *testing.T) {
+	tr := &RetryTransport{}
+	tr.init()
+
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set("Retry-After", "30")
+	tr.applyRateLimit(resp)
+
+	if wait := tr.rateLimitWait(""); wait <= 0 || wait > 30*time.Second {
+		t.Errorf("rateLimitWait(\"\") = %v, want roughly 30s", wait)
+	}
+	if wait := tr.rateLimitWait("any-category"); wait <= 0 || wait > 30*time.Second {
+		t.Errorf("rateLimitWait(%q) = %v, want project-wide limit applied", "any-category", wait)
+	}
+}
+
+// TestRetryTransportDeliverHonorsErrorCategoryLimit drives deliver (the
+// real send path), not rateLimitWait directly, to confirm a 429 naming
+// only the "error" category actually parks the next send - the category
+// deliver always checks since raven-go only ever sends error events.
+func TestRetryTransportDeliverHonorsErrorCategoryLimit(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("X-Sentry-Rate-Limits", "60:error")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	tr := &RetryTransport{Client: server.Client()}
+	tr.init()
+
+	req := sendRequest{url: server.URL, packet: NewPacket("boom")}
+	tr.deliver(req)
+	if hits != 1 {
+		t.Fatalf("hits after first deliver = %d, want 1", hits)
+	}
+
+	if wait := tr.rateLimitWait(errorCategory); wait <= 0 {
+		t.Fatalf("rateLimitWait(errorCategory) = %v, want a positive wait after a 429 naming it", wait)
+	}
+}
+
+func TestRetryTransportSetRateLimitKeepsLatest(t *testing.T) {
+	tr := &RetryTransport{}
+	tr.init()
+
+	now := time.Now()
+	tr.setRateLimit("error", now.Add(10*time.Second))
+	tr.setRateLimit("error", now.Add(5*time.Second)) // earlier: should not overwrite
+
+	if wait := tr.rateLimitWait("error"); wait < 9*time.Second {
+		t.Errorf("rateLimitWait(\"error\") = %v, want the later deadline to be kept", wait)
+	}
+}
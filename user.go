@@ -0,0 +1,12 @@
+package raven
+
+// User identifies the person affected by an event.
+// https://docs.getsentry.com/hosted/clientdev/interfaces/#user
+type User struct {
+	ID       string `json:"id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	IP       string `json:"ip_address,omitempty"`
+}
+
+func (u *User) Class() string { return "user" }